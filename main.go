@@ -2,13 +2,20 @@ package main
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
+	htmltemplate "html/template"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"text/template"
@@ -17,6 +24,9 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v3"
 	"howett.net/plist"
 )
 
@@ -52,27 +62,92 @@ const plistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 </dict>
 </plist>`
 
+const installPageTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>Install {{.AppName}}</title>
+</head>
+<body>
+    <h1>{{.AppName}}</h1>
+    {{if .IconURL}}<p><img src="{{.IconURL}}" alt="{{.AppName}} icon" width="120" height="120"></p>{{end}}
+    <p>Bundle ID: {{.BundleID}}</p>
+    <p>Version: {{.Version}}</p>
+    <p><a href="{{.InstallURL}}">Tap to Install</a></p>
+    <p>Or scan this QR code from an iOS device:</p>
+    <p><img src="{{.QRCodeURL}}" alt="Install QR code" width="200" height="200"></p>
+</body>
+</html>`
+
 var (
-	baseURL      string       // Base URL for download links, configurable via command line args or env vars
-	outputDir    = "./output" // Root directory for storing output files
-	port         string       // Server listening port
+	baseURL      string        // Base URL for download links, configurable via command line args or env vars
+	outputDir    = "./output"  // Root directory for storing output files
+	port         string        // Server listening port
+	plistProxy   string        // HTTPS relay URL used to front plist downloads for OTA installs, configurable via command line args or env vars
+	retention    time.Duration // How long to keep IPA work dirs before automatic cleanup
+
+	plistProxyAllowedHostsRaw string          // Comma-separated backend hosts plist-proxy may relay, configurable via command line args or env vars
+	plistProxyAllowedHosts    map[string]bool // Parsed from plistProxyAllowedHostsRaw; baseURL's own host is checked separately by isAllowedPlistURL
 	ipaCache     = make(map[string]IPAInfo)
 	ipaCacheLock sync.RWMutex
+
+	authType           string          // "none", "token", or "basic"
+	authTokensRaw      string          // Comma-separated bearer tokens, configurable via command line args or env vars
+	authTokens         map[string]bool // Parsed from authTokensRaw
+	basicAuthFile      string          // YAML file of username:password pairs for -auth-type=basic
+	basicAuthUsers     map[string]string
+	maxConcurrentSigns int     // Maximum concurrent signing requests per token/IP
+	signsPerHour       float64 // Maximum signing requests per hour per token/IP
 )
 
+// ipaMetaFilename is the name of the JSON file persisting each IPAInfo
+// alongside its work directory, so entries survive a server restart.
+const ipaMetaFilename = "meta.json"
+
 // IPAInfo stores information about analyzed IPA files
 type IPAInfo struct {
-	OriginalURL string
-	UUID        string
-	BundleID    string
-	AppName     string
-	UploadedAt  time.Time
+	OriginalURL  string
+	UUID         string
+	BundleID     string
+	AppName      string
+	Version      string
+	IconPath     string // filename of the extracted app icon within the work dir, empty if none found
+	SourceSHA256 string // hex SHA-256 digest of source.ipa
+	UploadedAt   time.Time
+}
+
+// iconFileRegexp matches icon asset filenames such as "AppIcon60x60@3x.png" so
+// the rendered pixel size of each candidate can be compared.
+var iconFileRegexp = regexp.MustCompile(`AppIcon.*?(\d+(?:\.\d+)?)x\d+(?:\.\d+)?(?:@(\d)x)?\.png$`)
+
+// iconScore estimates the rendered pixel size of an icon filename, preferring
+// larger point sizes and higher @Nx scale factors.
+func iconScore(name string) float64 {
+	m := iconFileRegexp.FindStringSubmatch(name)
+	if m == nil {
+		return -1
+	}
+	size, _ := strconv.ParseFloat(m[1], 64)
+	scale := 1.0
+	if m[2] != "" {
+		scale, _ = strconv.ParseFloat(m[2], 64)
+	}
+	return size * scale
 }
 
 func init() {
 	// Define command line flags
 	flag.StringVar(&baseURL, "base-url", "http://localhost:8080", "Base URL for generated download links")
 	flag.StringVar(&port, "port", "8080", "Port to listen on")
+	flag.StringVar(&plistProxy, "plist-proxy", "", "HTTPS relay URL that fetches and re-serves manifest plists for OTA installs (e.g. https://example.com/plist-proxy)")
+	flag.StringVar(&plistProxyAllowedHostsRaw, "plist-proxy-allowed-hosts", "", "Comma-separated backend hosts (host:port) the /plist-proxy endpoint is allowed to relay manifests from, in addition to this server's own -base-url host")
+	flag.DurationVar(&retention, "retention", 7*24*time.Hour, "How long to keep analyzed/resigned IPAs before their work directory is automatically removed")
+	flag.StringVar(&authType, "auth-type", "none", "Authentication mode for /resign and /analyze: none, token, or basic")
+	flag.StringVar(&authTokensRaw, "auth-tokens", "", "Comma-separated bearer tokens; when any are set, /resign and /analyze require one unless -auth-type=basic")
+	flag.StringVar(&basicAuthFile, "basic-auth-file", "", "YAML file of username: password pairs, used when -auth-type=basic")
+	flag.IntVar(&maxConcurrentSigns, "max-concurrent-signs", 2, "Maximum concurrent signing requests allowed per token/IP")
+	flag.Float64Var(&signsPerHour, "signs-per-hour", 30, "Maximum signing requests allowed per hour per token/IP")
 
 	// Parse command line arguments
 	flag.Parse()
@@ -87,12 +162,62 @@ func init() {
 		}
 	}
 
+	// If plist proxy is not set via command line, try environment variable
+	if plistProxy == "" {
+		plistProxy = os.Getenv("PLIST_PROXY")
+	}
+
+	// If the plist-proxy allowlist is not set via command line, try environment variable
+	if plistProxyAllowedHostsRaw == "" {
+		plistProxyAllowedHostsRaw = os.Getenv("PLIST_PROXY_ALLOWED_HOSTS")
+	}
+
+	// If auth tokens are not set via command line, try environment variable
+	if authTokensRaw == "" {
+		authTokensRaw = os.Getenv("AUTH_TOKENS")
+	}
+
 	// Ensure baseURL doesn't end with a slash
 	baseURL = strings.TrimRight(baseURL, "/")
+	plistProxy = strings.TrimRight(plistProxy, "/")
+
+	authTokens = make(map[string]bool)
+	for _, token := range strings.Split(authTokensRaw, ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			authTokens[token] = true
+		}
+	}
+
+	plistProxyAllowedHosts = make(map[string]bool)
+	for _, host := range strings.Split(plistProxyAllowedHostsRaw, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			plistProxyAllowedHosts[host] = true
+		}
+	}
+
+	if authType == "token" && len(authTokens) == 0 {
+		fmt.Println("Warning: auth-type is 'token' but no tokens were configured via -auth-tokens or AUTH_TOKENS; /resign and /analyze will refuse all requests")
+	}
+
+	if authType == "basic" {
+		users, err := loadBasicAuthUsers(basicAuthFile)
+		if err != nil {
+			fmt.Printf("Warning: failed to load basic auth users from %s: %v\n", basicAuthFile, err)
+		}
+		basicAuthUsers = users
+	}
 
 	// Print configuration info
 	fmt.Printf("Using BASE_URL: %s\n", baseURL)
 	fmt.Printf("Using PORT: %s\n", port)
+	if plistProxy != "" {
+		fmt.Printf("Using PLIST_PROXY: %s\n", plistProxy)
+	}
+	if len(plistProxyAllowedHosts) > 0 {
+		fmt.Printf("Using PLIST_PROXY_ALLOWED_HOSTS: %s\n", plistProxyAllowedHostsRaw)
+	}
+	fmt.Printf("Using RETENTION: %s\n", retention)
+	fmt.Printf("Using AUTH_TYPE: %s\n", authType)
 }
 
 func main() {
@@ -100,15 +225,29 @@ func main() {
 	r := gin.Default()
 	r.Use(cors.Default())
 
-	r.POST("/resign", resignHandler)
-	r.POST("/analyze", analyzeIPAHandler) // New endpoint for analyzing IPA files
+	// /resign and /analyze are CPU/IO heavy (zsign, downloads), so they're
+	// gated by authentication and per-token rate limiting; downloads stay
+	// public so install links keep working.
+	signing := r.Group("/")
+	signing.Use(authMiddleware(), signGatingMiddleware())
+	signing.POST("/resign", resignHandler)
+	signing.POST("/analyze", analyzeIPAHandler) // New endpoint for analyzing IPA files
+
 	r.GET("/download/:uuid/:filename", downloadHandler)
+	r.GET("/install/:uuid", installHandler)  // OTA install page with QR code
+	r.GET("/plist-proxy", plistProxyHandler) // HTTPS relay for manifest plists
+	r.GET("/ipas", listIPAsHandler)          // JSON index of known IPAs
 
 	// Ensure output directory exists
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		panic(err)
 	}
 
+	// Rebuild the in-memory cache from persisted metadata so a restart
+	// doesn't orphan previously shared download and install links
+	loadIPACache()
+	startRetentionSweeper()
+
 	// Start the server
 	fmt.Printf("Server starting on port %s...\n", port)
 	r.Run(":" + port)
@@ -136,49 +275,86 @@ func downloadFile(url, filepath string) error {
 	return err
 }
 
-// Extract Info.plist from IPA file and parse it
-func extractIPAInfo(ipaPath string) (string, string, error) {
+// computeSHA256 streams path through sha256 (rather than loading it fully
+// into memory) and returns the hex digest.
+func computeSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeSHA256File writes a sibling "<filePath>.sha256" file in the same
+// format as sha256sum: "<hex digest>  <filename>\n".
+func writeSHA256File(filePath, digest string) error {
+	content := fmt.Sprintf("%s  %s\n", digest, filepath.Base(filePath))
+	return os.WriteFile(filePath+".sha256", []byte(content), 0644)
+}
+
+// ipaMetadata holds the fields extracted from an IPA's Info.plist, plus the
+// on-disk location of any extracted app icon.
+type ipaMetadata struct {
+	BundleID string
+	AppName  string
+	Version  string
+	IconPath string
+}
+
+// Extract Info.plist from IPA file, parse it, and save the app icon (if any)
+// into workDir as "icon.png". The icon is copied through byte-for-byte: some
+// IPAs ship CgBI-encoded PNGs (Apple's modified PNG format), which consumers
+// can still render directly.
+func extractIPAInfo(ipaPath, workDir string) (ipaMetadata, error) {
 	reader, err := zip.OpenReader(ipaPath)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to open IPA file: %v", err)
+		return ipaMetadata{}, fmt.Errorf("failed to open IPA file: %v", err)
 	}
 	defer reader.Close()
 
 	var infoPlistFile *zip.File
+	appDir := ""
 	for _, file := range reader.File {
 		if strings.HasSuffix(file.Name, ".app/Info.plist") {
 			infoPlistFile = file
+			appDir = strings.TrimSuffix(file.Name, "Info.plist")
 			break
 		}
 	}
 
 	if infoPlistFile == nil {
-		return "", "", fmt.Errorf("info.plist not found in IPA")
+		return ipaMetadata{}, fmt.Errorf("info.plist not found in IPA")
 	}
 
 	// Open the plist file
 	rc, err := infoPlistFile.Open()
 	if err != nil {
-		return "", "", fmt.Errorf("failed to open Info.plist: %v", err)
+		return ipaMetadata{}, fmt.Errorf("failed to open Info.plist: %v", err)
 	}
 	defer rc.Close()
 
 	// Read the plist content
 	plistData, err := io.ReadAll(rc)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to read Info.plist: %v", err)
+		return ipaMetadata{}, fmt.Errorf("failed to read Info.plist: %v", err)
 	}
 
 	// Parse the plist
 	var plistObj map[string]interface{}
 	if _, err := plist.Unmarshal(plistData, &plistObj); err != nil {
-		return "", "", fmt.Errorf("failed to parse Info.plist: %v", err)
+		return ipaMetadata{}, fmt.Errorf("failed to parse Info.plist: %v", err)
 	}
 
 	// Extract bundle ID and app name
 	bundleID, ok := plistObj["CFBundleIdentifier"].(string)
 	if !ok {
-		return "", "", fmt.Errorf("CFBundleIdentifier not found or not a string")
+		return ipaMetadata{}, fmt.Errorf("CFBundleIdentifier not found or not a string")
 	}
 
 	appName, ok := plistObj["CFBundleDisplayName"].(string)
@@ -190,7 +366,350 @@ func extractIPAInfo(ipaPath string) (string, string, error) {
 		}
 	}
 
-	return bundleID, appName, nil
+	version, ok := plistObj["CFBundleShortVersionString"].(string)
+	if !ok {
+		version = "1.0"
+	}
+
+	iconPath := extractAppIcon(reader, appDir, plistObj, workDir)
+
+	return ipaMetadata{
+		BundleID: bundleID,
+		AppName:  appName,
+		Version:  version,
+		IconPath: iconPath,
+	}, nil
+}
+
+// extractAppIcon walks the zip for the app icon PNG under appDir, preferring
+// the filenames listed in CFBundleIcons.CFBundlePrimaryIcon.CFBundleIconFiles
+// when present and otherwise any "AppIcon*.png" asset, picking whichever
+// variant has the highest resolution. On success it saves the icon to
+// workDir/icon.png and returns "icon.png"; otherwise it returns "".
+func extractAppIcon(reader *zip.ReadCloser, appDir string, plistObj map[string]interface{}, workDir string) string {
+	var iconBaseNames []string
+	if icons, ok := plistObj["CFBundleIcons"].(map[string]interface{}); ok {
+		if primary, ok := icons["CFBundlePrimaryIcon"].(map[string]interface{}); ok {
+			if files, ok := primary["CFBundleIconFiles"].([]interface{}); ok {
+				for _, f := range files {
+					if name, ok := f.(string); ok {
+						iconBaseNames = append(iconBaseNames, name)
+					}
+				}
+			}
+		}
+	}
+
+	var bestFile *zip.File
+	// Start below iconScore's "no size in filename" value (-1) so a file
+	// matched purely by CFBundleIconFiles membership is still picked even
+	// when its name doesn't follow the AppIcon<size>x<size>@<scale>x.png
+	// convention that iconScore parses.
+	bestScore := -2.0
+	for _, file := range reader.File {
+		if !strings.HasPrefix(file.Name, appDir) || !strings.HasSuffix(file.Name, ".png") {
+			continue
+		}
+		base := filepath.Base(file.Name)
+		if len(iconBaseNames) > 0 {
+			matched := false
+			for _, ib := range iconBaseNames {
+				if strings.HasPrefix(base, ib) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		} else if !strings.Contains(base, "AppIcon") {
+			continue
+		}
+		if score := iconScore(base); score > bestScore {
+			bestScore = score
+			bestFile = file
+		}
+	}
+
+	if bestFile == nil {
+		return ""
+	}
+
+	if err := extractZipFileTo(bestFile, filepath.Join(workDir, "icon.png")); err != nil {
+		return ""
+	}
+	return "icon.png"
+}
+
+// extractZipFileTo copies a zip entry's raw bytes to destPath.
+func extractZipFileTo(file *zip.File, destPath string) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, rc)
+	return err
+}
+
+// saveIPAMeta persists info as workDir/meta.json so the entry survives a
+// server restart.
+func saveIPAMeta(workDir string, info IPAInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(workDir, ipaMetaFilename), data, 0644)
+}
+
+// loadIPACache rebuilds ipaCache from output/<uuid>/meta.json files written
+// by previous runs, falling back to re-parsing source.ipa via extractIPAInfo
+// when meta.json is missing.
+func loadIPACache() {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		uuidStr := entry.Name()
+		workDir := filepath.Join(outputDir, uuidStr)
+
+		metaPath := filepath.Join(workDir, ipaMetaFilename)
+		if data, err := os.ReadFile(metaPath); err == nil {
+			var info IPAInfo
+			if err := json.Unmarshal(data, &info); err == nil {
+				ipaCacheLock.Lock()
+				ipaCache[uuidStr] = info
+				ipaCacheLock.Unlock()
+				continue
+			}
+		}
+
+		// meta.json missing or unreadable: fall back to re-parsing source.ipa
+		sourceIpaPath := filepath.Join(workDir, "source.ipa")
+		if _, err := os.Stat(sourceIpaPath); err != nil {
+			continue
+		}
+		meta, err := extractIPAInfo(sourceIpaPath, workDir)
+		if err != nil {
+			continue
+		}
+
+		info := IPAInfo{
+			UUID:       uuidStr,
+			BundleID:   meta.BundleID,
+			AppName:    meta.AppName,
+			Version:    meta.Version,
+			IconPath:   meta.IconPath,
+			UploadedAt: time.Now(),
+		}
+		ipaCacheLock.Lock()
+		ipaCache[uuidStr] = info
+		ipaCacheLock.Unlock()
+
+		if err := saveIPAMeta(workDir, info); err != nil {
+			fmt.Printf("Warning: failed to persist metadata for %s: %v\n", uuidStr, err)
+		}
+	}
+
+	fmt.Printf("Loaded %d IPA(s) from %s\n", len(ipaCache), outputDir)
+}
+
+// startRetentionSweeper periodically evicts cached IPAs older than retention
+// and removes their work directories, so outputDir doesn't grow unbounded.
+func startRetentionSweeper() {
+	if retention <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(10 * time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			evictExpiredIPAs()
+		}
+	}()
+}
+
+// evictExpiredIPAs removes cache entries and work directories whose
+// UploadedAt is older than retention.
+func evictExpiredIPAs() {
+	cutoff := time.Now().Add(-retention)
+
+	ipaCacheLock.Lock()
+	defer ipaCacheLock.Unlock()
+	for uuidStr, info := range ipaCache {
+		if info.UploadedAt.Before(cutoff) {
+			delete(ipaCache, uuidStr)
+			os.RemoveAll(filepath.Join(outputDir, uuidStr))
+		}
+	}
+}
+
+// Handler listing all known IPAs with their metadata and download URLs.
+func listIPAsHandler(c *gin.Context) {
+	ipaCacheLock.RLock()
+	defer ipaCacheLock.RUnlock()
+
+	ipas := make([]gin.H, 0, len(ipaCache))
+	for _, info := range ipaCache {
+		ipas = append(ipas, gin.H{
+			"uuid":        info.UUID,
+			"bundle_id":   info.BundleID,
+			"app_name":    info.AppName,
+			"version":     info.Version,
+			"icon_url":    iconURL(info.UUID, info.IconPath),
+			"source_url":  fmt.Sprintf("%s/download/%s/source.ipa", baseURL, info.UUID),
+			"uploaded_at": info.UploadedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ipas": ipas})
+}
+
+// loadBasicAuthUsers reads a YAML file of "username: password" pairs used
+// for -auth-type=basic.
+func loadBasicAuthUsers(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no -basic-auth-file configured")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var users map[string]string
+	if err := yaml.Unmarshal(data, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// authMiddleware enforces -auth-type on the signing endpoints. Read-only
+// downloads are registered outside this group and remain public.
+func authMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authType == "basic" {
+			username, password, ok := c.Request.BasicAuth()
+			expected, known := basicAuthUsers[username]
+			if !ok || !known || expected != password {
+				c.Header("WWW-Authenticate", `Basic realm="resign"`)
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+				c.Abort()
+				return
+			}
+			c.Set("auth_identity", username)
+			c.Next()
+			return
+		}
+
+		// auth-type=token with no tokens configured is a misconfiguration,
+		// not an opt-out: fail closed instead of silently serving openly.
+		if authType == "token" && len(authTokens) == 0 {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "auth-type is 'token' but no tokens are configured"})
+			c.Abort()
+			return
+		}
+
+		// Bearer-token auth is enforced whenever any tokens are configured,
+		// even if -auth-type was left at its default "none" -- otherwise
+		// setting -auth-tokens without also setting -auth-type would
+		// silently leave /resign and /analyze public.
+		if len(authTokens) == 0 {
+			c.Next()
+			return
+		}
+
+		header := c.GetHeader("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid bearer token"})
+			c.Abort()
+			return
+		}
+		token := strings.TrimPrefix(header, "Bearer ")
+		if !authTokens[token] {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid bearer token"})
+			c.Abort()
+			return
+		}
+		c.Set("auth_identity", token)
+		c.Next()
+	}
+}
+
+// signUsage tracks per-identity usage of the signing endpoints: a
+// signs-per-hour token bucket plus a count of in-flight requests.
+type signUsage struct {
+	limiter    *rate.Limiter
+	mu         sync.Mutex
+	concurrent int
+}
+
+var (
+	signUsageByIdentity = make(map[string]*signUsage)
+	signUsageLock       sync.Mutex
+)
+
+// usageFor returns the signUsage for identity, creating it on first use.
+func usageFor(identity string) *signUsage {
+	signUsageLock.Lock()
+	defer signUsageLock.Unlock()
+
+	usage, ok := signUsageByIdentity[identity]
+	if !ok {
+		usage = &signUsage{limiter: rate.NewLimiter(rate.Limit(signsPerHour/3600), int(signsPerHour))}
+		signUsageByIdentity[identity] = usage
+	}
+	return usage
+}
+
+// signGatingMiddleware enforces max-concurrent-signs and signs-per-hour per
+// identity (the authenticated token/username, or the client IP when no
+// authentication is configured), returning 429 when either is exceeded.
+func signGatingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := c.ClientIP()
+		if v, ok := c.Get("auth_identity"); ok {
+			identity = v.(string)
+		}
+
+		usage := usageFor(identity)
+
+		if !usage.limiter.Allow() {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded: too many signing requests this hour"})
+			c.Abort()
+			return
+		}
+
+		usage.mu.Lock()
+		if usage.concurrent >= maxConcurrentSigns {
+			usage.mu.Unlock()
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many concurrent signing requests"})
+			c.Abort()
+			return
+		}
+		usage.concurrent++
+		usage.mu.Unlock()
+
+		defer func() {
+			usage.mu.Lock()
+			usage.concurrent--
+			usage.mu.Unlock()
+		}()
+
+		c.Next()
+	}
 }
 
 // Handler for the new analyze endpoint
@@ -208,11 +727,13 @@ func analyzeIPAHandler(c *gin.Context) {
 		if info.OriginalURL == ipaURL {
 			ipaCacheLock.RUnlock()
 			c.JSON(http.StatusOK, gin.H{
-				"uuid":       info.UUID,
-				"bundle_id":  info.BundleID,
-				"app_name":   info.AppName,
-				"source_url": fmt.Sprintf("%s/download/%s/source.ipa", baseURL, info.UUID),
-				"analyzed":   true,
+				"uuid":          info.UUID,
+				"bundle_id":     info.BundleID,
+				"app_name":      info.AppName,
+				"source_url":    fmt.Sprintf("%s/download/%s/source.ipa", baseURL, info.UUID),
+				"icon_url":      iconURL(info.UUID, info.IconPath),
+				"source_sha256": info.SourceSHA256,
+				"analyzed":      true,
 			})
 			return
 		}
@@ -238,8 +759,25 @@ func analyzeIPAHandler(c *gin.Context) {
 		return
 	}
 
-	// Extract bundle ID and app name
-	bundleID, appName, err := extractIPAInfo(ipaPath)
+	sourceSHA256, err := computeSHA256(ipaPath)
+	if err != nil {
+		os.RemoveAll(workDir)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to checksum IPA file: " + err.Error()})
+		return
+	}
+
+	if expected := c.PostForm("expected_sha256"); expected != "" && !strings.EqualFold(expected, sourceSHA256) {
+		os.RemoveAll(workDir)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Downloaded IPA does not match expected_sha256"})
+		return
+	}
+
+	if err := writeSHA256File(ipaPath, sourceSHA256); err != nil {
+		fmt.Printf("Warning: failed to write checksum file for %s: %v\n", uuidStr, err)
+	}
+
+	// Extract bundle ID, app name, version, and the app icon
+	meta, err := extractIPAInfo(ipaPath, workDir)
 	if err != nil {
 		// If extraction fails, delete the downloaded file
 		os.RemoveAll(workDir)
@@ -248,26 +786,45 @@ func analyzeIPAHandler(c *gin.Context) {
 	}
 
 	// Store the IPA info in cache
-	ipaCacheLock.Lock()
-	ipaCache[uuidStr] = IPAInfo{
-		OriginalURL: ipaURL,
-		UUID:        uuidStr,
-		BundleID:    bundleID,
-		AppName:     appName,
-		UploadedAt:  time.Now(),
+	info := IPAInfo{
+		OriginalURL:  ipaURL,
+		UUID:         uuidStr,
+		BundleID:     meta.BundleID,
+		AppName:      meta.AppName,
+		Version:      meta.Version,
+		IconPath:     meta.IconPath,
+		SourceSHA256: sourceSHA256,
+		UploadedAt:   time.Now(),
 	}
+	ipaCacheLock.Lock()
+	ipaCache[uuidStr] = info
 	ipaCacheLock.Unlock()
 
+	if err := saveIPAMeta(workDir, info); err != nil {
+		fmt.Printf("Warning: failed to persist metadata for %s: %v\n", uuidStr, err)
+	}
+
 	// Return the UUID and extracted info
 	c.JSON(http.StatusOK, gin.H{
-		"uuid":       uuidStr,
-		"bundle_id":  bundleID,
-		"app_name":   appName,
-		"source_url": fmt.Sprintf("%s/download/%s/source.ipa", baseURL, uuidStr),
-		"analyzed":   true,
+		"uuid":          uuidStr,
+		"bundle_id":     meta.BundleID,
+		"app_name":      meta.AppName,
+		"source_url":    fmt.Sprintf("%s/download/%s/source.ipa", baseURL, uuidStr),
+		"icon_url":      iconURL(uuidStr, meta.IconPath),
+		"source_sha256": sourceSHA256,
+		"analyzed":      true,
 	})
 }
 
+// iconURL builds the download URL for an extracted app icon, or "" if none
+// was found.
+func iconURL(uuidStr, iconPath string) string {
+	if iconPath == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/download/%s/%s", baseURL, uuidStr, iconPath)
+}
+
 // Handler for downloading files
 func downloadHandler(c *gin.Context) {
 	uuid := c.Param("uuid")
@@ -286,6 +843,10 @@ func downloadHandler(c *gin.Context) {
 		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
 	} else if strings.HasSuffix(filename, ".plist") {
 		c.Header("Content-Type", "application/xml")
+	} else if strings.HasSuffix(filename, ".png") {
+		c.Header("Content-Type", "image/png")
+	} else if strings.HasSuffix(filename, ".sha256") {
+		c.Header("Content-Type", "text/plain")
 	}
 
 	c.File(filePath)
@@ -296,7 +857,7 @@ func resignHandler(c *gin.Context) {
 	// Get UUID for the IPA to resign
 	var uuidStr string
 	var sourceIpaPath string
-	var bundleID, appName string
+	var bundleID, appName, version, iconPath, sourceSHA256 string
 	var workDir string
 
 	ipaUUID := c.PostForm("ipa_uuid")
@@ -333,6 +894,10 @@ func resignHandler(c *gin.Context) {
 			appName = info.AppName
 		}
 
+		version = info.Version
+		iconPath = info.IconPath
+		sourceSHA256 = info.SourceSHA256
+
 	} else if ipaURL != "" {
 		// Create a new UUID for this IPA
 		uuidStr = uuid.New().String()
@@ -352,33 +917,57 @@ func resignHandler(c *gin.Context) {
 			return
 		}
 
+		digest, err := computeSHA256(sourceIpaPath)
+		if err != nil {
+			os.RemoveAll(workDir)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to checksum IPA file: " + err.Error()})
+			return
+		}
+		if expected := c.PostForm("expected_sha256"); expected != "" && !strings.EqualFold(expected, digest) {
+			os.RemoveAll(workDir)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Downloaded IPA does not match expected_sha256"})
+			return
+		}
+		if err := writeSHA256File(sourceIpaPath, digest); err != nil {
+			fmt.Printf("Warning: failed to write checksum file for %s: %v\n", uuidStr, err)
+		}
+		sourceSHA256 = digest
+
 		// Get bundle ID and app name from parameters
 		bundleID = c.PostForm("bundle_id")
 		appName = c.PostForm("app_name")
 
 		// If not provided, try to extract from IPA
-		if bundleID == "" || appName == "" {
-			extractedBundleID, extractedAppName, err := extractIPAInfo(sourceIpaPath)
-			if err == nil {
-				if bundleID == "" {
-					bundleID = extractedBundleID
-				}
-				if appName == "" {
-					appName = extractedAppName
-				}
+		meta, err := extractIPAInfo(sourceIpaPath, workDir)
+		if err == nil {
+			if bundleID == "" {
+				bundleID = meta.BundleID
+			}
+			if appName == "" {
+				appName = meta.AppName
 			}
+			version = meta.Version
+			iconPath = meta.IconPath
 		}
 
 		// Store the IPA info in cache
-		ipaCacheLock.Lock()
-		ipaCache[uuidStr] = IPAInfo{
-			OriginalURL: ipaURL,
-			UUID:        uuidStr,
-			BundleID:    bundleID,
-			AppName:     appName,
-			UploadedAt:  time.Now(),
+		info := IPAInfo{
+			OriginalURL:  ipaURL,
+			UUID:         uuidStr,
+			BundleID:     bundleID,
+			AppName:      appName,
+			Version:      version,
+			IconPath:     iconPath,
+			SourceSHA256: sourceSHA256,
+			UploadedAt:   time.Now(),
 		}
+		ipaCacheLock.Lock()
+		ipaCache[uuidStr] = info
 		ipaCacheLock.Unlock()
+
+		if err := saveIPAMeta(workDir, info); err != nil {
+			fmt.Printf("Warning: failed to persist metadata for %s: %v\n", uuidStr, err)
+		}
 	} else {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Either ipa_url or ipa_uuid must be provided"})
 		return
@@ -449,6 +1038,15 @@ func resignHandler(c *gin.Context) {
 		return
 	}
 
+	ipaSHA256, err := computeSHA256(outputPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to checksum resigned IPA: " + err.Error()})
+		return
+	}
+	if err := writeSHA256File(outputPath, ipaSHA256); err != nil {
+		fmt.Printf("Warning: failed to write checksum file for %s: %v\n", uuidStr, err)
+	}
+
 	// Generate plist file with fixed name
 	plistPath := filepath.Join(workDir, "manifest.plist")
 	ipaDownloadURL := fmt.Sprintf("%s/download/%s/resigned.ipa", baseURL, uuidStr)
@@ -463,18 +1061,187 @@ func resignHandler(c *gin.Context) {
 	plistURL := fmt.Sprintf("%s/download/%s/manifest.plist", baseURL, uuidStr)
 	sourceURL := fmt.Sprintf("%s/download/%s/source.ipa", baseURL, uuidStr)
 	resignedURL := fmt.Sprintf("%s/download/%s/resigned.ipa", baseURL, uuidStr)
+	resolvedPlistURL := resolvePlistURL(plistURL)
+	installURL := itmsServicesURL(resolvedPlistURL)
+
+	// Generate the OTA install QR code alongside the manifest
+	qrcodePath := filepath.Join(workDir, "qrcode.png")
+	if err := generateQRCode(installURL, qrcodePath); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate QR code"})
+		return
+	}
 
 	// Return the download URLs
 	c.JSON(http.StatusOK, gin.H{
-		"uuid":       uuidStr,
-		"plist_url":  plistURL,
-		"source_url": sourceURL,
-		"ipa_url":    resignedURL,
-		"bundle_id":  bundleID,
-		"app_name":   appName,
+		"uuid":          uuidStr,
+		"plist_url":     resolvedPlistURL,
+		"source_url":    sourceURL,
+		"ipa_url":       resignedURL,
+		"icon_url":      iconURL(uuidStr, iconPath),
+		"install_url":   installURL,
+		"qrcode_url":    fmt.Sprintf("%s/download/%s/qrcode.png", baseURL, uuidStr),
+		"bundle_id":     bundleID,
+		"app_name":      appName,
+		"source_sha256": sourceSHA256,
+		"ipa_sha256":    ipaSHA256,
 	})
 }
 
+// itmsServicesURL builds the itms-services:// link iOS uses to trigger an
+// over-the-air install from a manifest plist URL.
+func itmsServicesURL(plistURL string) string {
+	return fmt.Sprintf("itms-services://?action=download-manifest&url=%s", url.QueryEscape(plistURL))
+}
+
+// resolvePlistURL returns the URL a device should fetch the manifest plist
+// from. iOS requires this URL to be HTTPS, so when -plist-proxy is
+// configured, the direct (possibly plain-HTTP) download URL is wrapped so a
+// public HTTPS relay can fetch and re-serve it instead.
+func resolvePlistURL(plistURL string) string {
+	if plistProxy == "" {
+		return plistURL
+	}
+	return fmt.Sprintf("%s?url=%s", plistProxy, url.QueryEscape(plistURL))
+}
+
+// isRequestHTTPS reports whether the incoming request reached this server
+// over HTTPS, either directly via TLS or via a TLS-terminating reverse proxy
+// that sets X-Forwarded-Proto.
+func isRequestHTTPS(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// isAllowedPlistURL reports whether target is a manifest plist plist-proxy is
+// permitted to relay: a "/download/<uuid>/manifest.plist" path on either this
+// server's own -base-url host or one of the -plist-proxy-allowed-hosts, so a
+// single HTTPS-terminated instance can front multiple plain-HTTP backends.
+// plist-proxy only exists to give those backends an HTTPS front door for
+// their own manifests, not to fetch arbitrary URLs, so anything else
+// (including internal/metadata hosts) is rejected to avoid turning the
+// endpoint into an open SSRF relay.
+func isAllowedPlistURL(target string) bool {
+	targetURL, err := url.Parse(target)
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") {
+		return false
+	}
+
+	if !strings.HasPrefix(targetURL.Path, "/download/") || !strings.HasSuffix(targetURL.Path, "/manifest.plist") {
+		return false
+	}
+
+	if base, err := url.Parse(baseURL); err == nil && targetURL.Host == base.Host {
+		return true
+	}
+
+	return plistProxyAllowedHosts[targetURL.Host]
+}
+
+// Handler that fetches a plist URL and streams it back, letting a single
+// HTTPS-terminated instance front plist manifests served over plain HTTP by
+// other backends. Refuses to run unless it is itself reached over HTTPS,
+// since its only purpose is to provide an HTTPS source for itms-services
+// installs.
+func plistProxyHandler(c *gin.Context) {
+	if !isRequestHTTPS(c.Request) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "plist-proxy must be reached over HTTPS"})
+		return
+	}
+
+	target := c.Query("url")
+	if target == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing url parameter"})
+		return
+	}
+
+	if !isAllowedPlistURL(target) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "url is not a recognized manifest plist for this server"})
+		return
+	}
+
+	resp, err := http.Get(target)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch plist: " + err.Error()})
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("upstream returned %s", resp.Status)})
+		return
+	}
+
+	c.Header("Content-Type", "application/xml")
+	io.Copy(c.Writer, resp.Body)
+}
+
+// generateQRCode encodes content as a PNG QR code and writes it to destPath.
+func generateQRCode(content, destPath string) error {
+	return qrcode.WriteFile(content, qrcode.Medium, 256, destPath)
+}
+
+// Handler for the OTA install page: shows the app name, icon, bundle ID, and
+// version alongside the itms-services link and a QR code encoding it, so the
+// page can be opened directly on an iOS device or scanned from one.
+func installHandler(c *gin.Context) {
+	uuidStr := c.Param("uuid")
+
+	ipaCacheLock.RLock()
+	info, exists := ipaCache[uuidStr]
+	ipaCacheLock.RUnlock()
+	if !exists {
+		c.String(http.StatusNotFound, "IPA not found")
+		return
+	}
+
+	workDir := filepath.Join(outputDir, uuidStr)
+	manifestPath := filepath.Join(workDir, "manifest.plist")
+	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
+		c.String(http.StatusNotFound, "This IPA has not been resigned yet")
+		return
+	}
+
+	plistURL := fmt.Sprintf("%s/download/%s/manifest.plist", baseURL, uuidStr)
+	installURL := itmsServicesURL(resolvePlistURL(plistURL))
+
+	qrcodePath := filepath.Join(workDir, "qrcode.png")
+	if err := generateQRCode(installURL, qrcodePath); err != nil {
+		c.String(http.StatusInternalServerError, "Failed to generate QR code")
+		return
+	}
+
+	tmpl, err := htmltemplate.New("install").Parse(installPageTemplate)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to render install page")
+		return
+	}
+
+	var page strings.Builder
+	err = tmpl.Execute(&page, struct {
+		AppName    string
+		BundleID   string
+		Version    string
+		IconURL    string
+		InstallURL htmltemplate.URL // trusted, so html/template keeps the itms-services scheme
+		QRCodeURL  string
+	}{
+		AppName:    info.AppName,
+		BundleID:   info.BundleID,
+		Version:    info.Version,
+		IconURL:    iconURL(uuidStr, info.IconPath),
+		InstallURL: htmltemplate.URL(installURL),
+		QRCodeURL:  fmt.Sprintf("%s/download/%s/qrcode.png", baseURL, uuidStr),
+	})
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to render install page")
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
 // Generate plist file content using the template
 func generatePlist(ipaURL, bundleID, appName string) string {
 	tmpl, err := template.New("plist").Parse(plistTemplate)